@@ -2,15 +2,14 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
+// Package arch defines architecture-specific information and support functions.
 package arch
 
 import (
 	"cmd/internal/obj"
-	"cmd/internal/obj/arm"
-	"cmd/internal/obj/i386" // == 386
-	"cmd/internal/obj/ppc64"
-	"cmd/internal/obj/x86" // == amd64
 	"fmt"
+	"sort"
+	"strings"
 )
 
 // Pseudo-registers whose names are the constant name without the leading R.
@@ -36,6 +35,21 @@ type Arch struct {
 	IsJump func(word string) bool
 	// Aconv pretty-prints an instruction opcode for this architecture.
 	Aconv func(int) string
+	// Dconv pretty-prints an instruction operand for this architecture.
+	// If an Arch factory leaves it nil, Set fills in a generic
+	// implementation built from ReverseRegister; no arch package in this
+	// tree currently supplies its own, so today that generic path is
+	// always what callers get.
+	Dconv func(a *obj.Addr) string
+	// Pconv pretty-prints an entire instruction (obj.Prog) for this
+	// architecture. If an Arch factory leaves it nil, Set fills in a
+	// generic implementation built from Dconv and Aconv.
+	Pconv func(p *obj.Prog) string
+	// ReverseRegister maps a register enumeration value back to its
+	// assembler-syntax name, the inverse of Register. It is filled in by
+	// Set once the Arch is fully constructed, so factories don't each
+	// need to build it themselves.
+	ReverseRegister map[int16]string
 }
 
 // nilRegisterNumber is the register number function for architectures
@@ -52,265 +66,152 @@ var Pseudos = map[string]int{
 	"TEXT":     obj.ATEXT,
 }
 
-// Set configures the architecture specified by GOARCH and returns its representation.
-// It returns nil if GOARCH is not recognized.
-func Set(GOARCH string) *Arch {
-	switch GOARCH {
-	case "386":
-		return arch386()
-	case "amd64":
-		return archAmd64()
-	case "amd64p32":
-		a := archAmd64()
-		a.LinkArch = &x86.Linkamd64p32
-		return a
-	case "arm":
-		return archArm()
-	case "ppc64":
-		a := archPPC64()
-		a.LinkArch = &ppc64.Linkppc64
-		return a
-	case "ppc64le":
-		a := archPPC64()
-		a.LinkArch = &ppc64.Linkppc64le
-		return a
-	}
-	return nil
-}
-
-func jump386(word string) bool {
-	return word[0] == 'J' || word == "CALL"
-}
+// archs holds the registry of known GOARCH values to the Arch factories
+// that build their representation. Each architecture registers itself
+// from an init function in its own arch_*.go file.
+var archs = make(map[string]func() *Arch)
 
-func arch386() *Arch {
-	register := make(map[string]int16)
-	// Create maps for easy lookup of instruction names etc.
-	// TODO: Should this be done in obj for us?
-	for i, s := range i386.Register {
-		register[s] = int16(i + i386.REG_AL)
-	}
-	// Pseudo-registers.
-	register["SB"] = RSB
-	register["FP"] = RFP
-	register["PC"] = RPC
-	// Prefixes not used on this architecture.
-
-	instructions := make(map[string]int)
-	for i, s := range i386.Anames {
-		instructions[s] = i
-	}
-	// Annoying aliases.
-	instructions["JA"] = i386.AJHI
-	instructions["JAE"] = i386.AJCC
-	instructions["JB"] = i386.AJCS
-	instructions["JBE"] = i386.AJLS
-	instructions["JC"] = i386.AJCS
-	instructions["JE"] = i386.AJEQ
-	instructions["JG"] = i386.AJGT
-	instructions["JHS"] = i386.AJCC
-	instructions["JL"] = i386.AJLT
-	instructions["JLO"] = i386.AJCS
-	instructions["JNA"] = i386.AJLS
-	instructions["JNAE"] = i386.AJCS
-	instructions["JNB"] = i386.AJCC
-	instructions["JNBE"] = i386.AJHI
-	instructions["JNC"] = i386.AJCC
-	instructions["JNG"] = i386.AJLE
-	instructions["JNGE"] = i386.AJLT
-	instructions["JNL"] = i386.AJGE
-	instructions["JNLE"] = i386.AJGT
-	instructions["JNO"] = i386.AJOC
-	instructions["JNP"] = i386.AJPC
-	instructions["JNS"] = i386.AJPL
-	instructions["JNZ"] = i386.AJNE
-	instructions["JO"] = i386.AJOS
-	instructions["JP"] = i386.AJPS
-	instructions["JPE"] = i386.AJPS
-	instructions["JPO"] = i386.AJPC
-	instructions["JS"] = i386.AJMI
-	instructions["JZ"] = i386.AJEQ
-	instructions["MASKMOVDQU"] = i386.AMASKMOVOU
-	instructions["MOVOA"] = i386.AMOVO
-	instructions["MOVNTDQ"] = i386.AMOVNTO
-
-	return &Arch{
-		LinkArch:       &i386.Link386,
-		Instructions:   instructions,
-		Register:       register,
-		RegisterPrefix: nil,
-		RegisterNumber: nilRegisterNumber,
-		IsJump:         jump386,
-		Aconv:          i386.Aconv,
+// Register adds goarch and its Arch factory to the set known to Set and
+// List. It panics if goarch is already registered, so out-of-tree
+// architectures (or test fakes) must pick a GOARCH name that doesn't
+// collide with a standard one.
+func Register(goarch string, factory func() *Arch) {
+	if _, dup := archs[goarch]; dup {
+		panic("arch: Register called twice for " + goarch)
 	}
+	archs[goarch] = factory
 }
 
-func archAmd64() *Arch {
-	register := make(map[string]int16)
-	// Create maps for easy lookup of instruction names etc.
-	// TODO: Should this be done in obj for us?
-	for i, s := range x86.Register {
-		register[s] = int16(i + x86.REG_AL)
+// Set configures the architecture specified by GOARCH and returns its representation.
+// It returns nil if GOARCH is not recognized.
+func Set(GOARCH string) *Arch {
+	factory, ok := archs[GOARCH]
+	if !ok {
+		return nil
 	}
-	// Pseudo-registers.
-	register["SB"] = RSB
-	register["FP"] = RFP
-	register["PC"] = RPC
-	// Register prefix not used on this architecture.
-
-	instructions := make(map[string]int)
-	for i, s := range x86.Anames {
-		instructions[s] = i
+	a := factory()
+	a.ReverseRegister = reverseRegister(a.Register)
+	if a.Dconv == nil {
+		a.Dconv = genericDconv(a)
 	}
-	// Annoying aliases.
-	instructions["JA"] = x86.AJHI
-	instructions["JAE"] = x86.AJCC
-	instructions["JB"] = x86.AJCS
-	instructions["JBE"] = x86.AJLS
-	instructions["JC"] = x86.AJCS
-	instructions["JE"] = x86.AJEQ
-	instructions["JG"] = x86.AJGT
-	instructions["JHS"] = x86.AJCC
-	instructions["JL"] = x86.AJLT
-	instructions["JLO"] = x86.AJCS
-	instructions["JNA"] = x86.AJLS
-	instructions["JNAE"] = x86.AJCS
-	instructions["JNB"] = x86.AJCC
-	instructions["JNBE"] = x86.AJHI
-	instructions["JNC"] = x86.AJCC
-	instructions["JNG"] = x86.AJLE
-	instructions["JNGE"] = x86.AJLT
-	instructions["JNL"] = x86.AJGE
-	instructions["JNLE"] = x86.AJGT
-	instructions["JNO"] = x86.AJOC
-	instructions["JNP"] = x86.AJPC
-	instructions["JNS"] = x86.AJPL
-	instructions["JNZ"] = x86.AJNE
-	instructions["JO"] = x86.AJOS
-	instructions["JP"] = x86.AJPS
-	instructions["JPE"] = x86.AJPS
-	instructions["JPO"] = x86.AJPC
-	instructions["JS"] = x86.AJMI
-	instructions["JZ"] = x86.AJEQ
-	instructions["MASKMOVDQU"] = x86.AMASKMOVOU
-	instructions["MOVD"] = x86.AMOVQ
-	instructions["MOVDQ2Q"] = x86.AMOVQ
-	instructions["MOVNTDQ"] = x86.AMOVNTO
-	instructions["MOVOA"] = x86.AMOVO
-	instructions["MOVOA"] = x86.AMOVO
-	instructions["PF2ID"] = x86.APF2IL
-	instructions["PI2FD"] = x86.API2FL
-	instructions["PSLLDQ"] = x86.APSLLO
-	instructions["PSRLDQ"] = x86.APSRLO
-
-	return &Arch{
-		LinkArch:       &x86.Linkamd64,
-		Instructions:   instructions,
-		Register:       register,
-		RegisterPrefix: nil,
-		RegisterNumber: nilRegisterNumber,
-		IsJump:         jump386,
-		Aconv:          x86.Aconv,
+	if a.Pconv == nil {
+		a.Pconv = genericPconv(a)
 	}
+	return a
 }
 
-func archArm() *Arch {
-	register := make(map[string]int16)
-	// Create maps for easy lookup of instruction names etc.
-	// TODO: Should this be done in obj for us?
-	// Note that there is no list of names as there is for 386 and amd64.
-	// TODO: Are there aliases we need to add?
-	for i := arm.REG_R0; i < arm.REG_SPSR; i++ {
-		register[obj.Rconv(i)] = int16(i)
-	}
-	// Avoid unintentionally clobbering g using R10.
-	delete(register, "R10")
-	register["g"] = arm.REG_R10
-	for i := 0; i < 16; i++ {
-		register[fmt.Sprintf("C%d", i)] = int16(i)
-	}
-
-	// Pseudo-registers.
-	register["SB"] = RSB
-	register["FP"] = RFP
-	register["PC"] = RPC
-	register["SP"] = RSP
-	registerPrefix := map[string]bool{
-		"F": true,
-		"R": true,
-	}
+// reverseRegister builds the inverse of a Register map, so a register
+// enumeration value can be turned back into its assembler-syntax name.
+// Names such as "g" that alias a reserved register (R10 on arm, R30 on
+// ppc64, R28 on arm64) win automatically: the aliased numeric name (say
+// "R10") was deleted from Register before "g" was added, so only "g"
+// is left to be picked up here. Where Register keeps several names for
+// one number (on arm64, "LR" and "R30", "ZR" and "R31"), which of them
+// ends up in the result is not defined.
+func reverseRegister(register map[string]int16) map[int16]string {
+	reverse := make(map[int16]string, len(register))
+	for name, num := range register {
+		reverse[num] = name
+	}
+	return reverse
+}
 
-	instructions := make(map[string]int)
-	for i, s := range arm.Anames {
-		instructions[s] = i
+// genericDconv returns a Dconv for architectures whose obj package does
+// not provide its own operand printer. It switches on addr.Type the way
+// obj's own instruction printer does, so it handles the common operand
+// shapes (registers, memory, symbols, branch targets, constants)
+// instead of only ever printing a register name or a bare "$offset".
+func genericDconv(a *Arch) func(*obj.Addr) string {
+	return func(addr *obj.Addr) string {
+		switch addr.Type {
+		case obj.TYPE_NONE:
+			return ""
+		case obj.TYPE_REG:
+			return genericRconv(a, addr.Reg)
+		case obj.TYPE_MEM:
+			return genericMconv(a, addr)
+		case obj.TYPE_BRANCH:
+			if addr.Sym != nil {
+				return addr.Sym.Name
+			}
+			return fmt.Sprintf("%d", addr.Offset)
+		case obj.TYPE_FCONST:
+			return fmt.Sprintf("$%v", addr.Val)
+		case obj.TYPE_SCONST:
+			return fmt.Sprintf("$%q", addr.Val)
+		case obj.TYPE_CONST, obj.TYPE_ADDR:
+			if addr.Sym != nil {
+				return fmt.Sprintf("$%s+%d(SB)", addr.Sym.Name, addr.Offset)
+			}
+			return fmt.Sprintf("$%d", addr.Offset)
+		}
+		return fmt.Sprintf("$%d", addr.Offset)
 	}
-	// Annoying aliases.
-	instructions["B"] = obj.AJMP
-	instructions["BL"] = obj.ACALL
+}
 
-	return &Arch{
-		LinkArch:       &arm.Linkarm,
-		Instructions:   instructions,
-		Register:       register,
-		RegisterPrefix: registerPrefix,
-		RegisterNumber: armRegisterNumber,
-		IsJump:         jumpArm,
-		Aconv:          arm.Aconv,
+// genericRconv prints a register by name, falling back to a numeric
+// placeholder for a value ReverseRegister doesn't know about.
+func genericRconv(a *Arch, reg int16) string {
+	if name, ok := a.ReverseRegister[reg]; ok {
+		return name
 	}
+	return fmt.Sprintf("R???%d", reg)
 }
 
-func archPPC64() *Arch {
-	register := make(map[string]int16)
-	// Create maps for easy lookup of instruction names etc.
-	// TODO: Should this be done in obj for us?
-	// Note that there is no list of names as there is for 386 and amd64.
-	for i := ppc64.REG_R0; i <= ppc64.REG_R31; i++ {
-		register[obj.Rconv(i)] = int16(i)
-	}
-	for i := ppc64.REG_F0; i <= ppc64.REG_F31; i++ {
-		register[obj.Rconv(i)] = int16(i)
-	}
-	for i := ppc64.REG_CR0; i <= ppc64.REG_CR7; i++ {
-		register[obj.Rconv(i)] = int16(i)
-	}
-	for i := ppc64.REG_MSR; i <= ppc64.REG_CR; i++ {
-		register[obj.Rconv(i)] = int16(i)
-	}
-	register["CR"] = ppc64.REG_CR
-	register["XER"] = ppc64.REG_XER
-	register["LR"] = ppc64.REG_LR
-	register["CTR"] = ppc64.REG_CTR
-	register["FPSCR"] = ppc64.REG_FPSCR
-	register["MSR"] = ppc64.REG_MSR
-	// Pseudo-registers.
-	register["SB"] = RSB
-	register["FP"] = RFP
-	register["PC"] = RPC
-	// Avoid unintentionally clobbering g using R30.
-	delete(register, "R30")
-	register["g"] = ppc64.REG_R30
-	registerPrefix := map[string]bool{
-		"CR":  true,
-		"F":   true,
-		"R":   true,
-		"SPR": true,
-	}
+// genericMconv prints a memory operand: a symbol reference such as
+// foo+8(SB) for extern/static names, or an offset(reg) form such as
+// 8(R3) otherwise.
+func genericMconv(a *Arch, addr *obj.Addr) string {
+	switch addr.Name {
+	case obj.NAME_EXTERN, obj.NAME_STATIC:
+		if addr.Sym != nil {
+			if addr.Offset != 0 {
+				return fmt.Sprintf("%s+%d(SB)", addr.Sym.Name, addr.Offset)
+			}
+			return fmt.Sprintf("%s(SB)", addr.Sym.Name)
+		}
+	}
+	reg := genericRconv(a, addr.Reg)
+	if addr.Offset != 0 {
+		return fmt.Sprintf("%d(%s)", addr.Offset, reg)
+	}
+	return fmt.Sprintf("(%s)", reg)
+}
 
-	instructions := make(map[string]int)
-	for i, s := range ppc64.Anames {
-		instructions[s] = i
+// genericPconv returns a Pconv built from Aconv and Dconv for
+// architectures whose obj package does not provide its own instruction
+// printer. It mirrors obj's own printing rules: operands whose Type is
+// TYPE_NONE are omitted rather than printed as a bogus "$0", p.Reg (the
+// implicit middle register some instructions carry) is included when
+// set, and any p.RestArgs are appended in order.
+func genericPconv(a *Arch) func(*obj.Prog) string {
+	return func(p *obj.Prog) string {
+		var operands []string
+		if p.From.Type != obj.TYPE_NONE {
+			operands = append(operands, a.Dconv(&p.From))
+		}
+		if p.Reg != 0 {
+			operands = append(operands, genericRconv(a, p.Reg))
+		}
+		for i := range p.RestArgs {
+			operands = append(operands, a.Dconv(&p.RestArgs[i]))
+		}
+		if p.To.Type != obj.TYPE_NONE {
+			operands = append(operands, a.Dconv(&p.To))
+		}
+		if len(operands) == 0 {
+			return a.Aconv(int(p.As))
+		}
+		return a.Aconv(int(p.As)) + " " + strings.Join(operands, ", ")
 	}
-	// Annoying aliases.
-	instructions["BR"] = ppc64.ABR
-	instructions["BL"] = ppc64.ABL
-	instructions["RETURN"] = ppc64.ARETURN
+}
 
-	return &Arch{
-		LinkArch:       &ppc64.Linkppc64,
-		Instructions:   instructions,
-		Register:       register,
-		RegisterPrefix: registerPrefix,
-		RegisterNumber: ppc64RegisterNumber,
-		IsJump:         jumpPPC64,
-		Aconv:          ppc64.Aconv,
+// List returns the sorted list of GOARCH values known to Set.
+func List() []string {
+	list := make([]string, 0, len(archs))
+	for goarch := range archs {
+		list = append(list, goarch)
 	}
+	sort.Strings(list)
+	return list
 }
@@ -0,0 +1,77 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package arch
+
+import "cmd/internal/obj/i386"
+
+func init() {
+	Register("386", arch386)
+}
+
+func jump386(word string) bool {
+	return word[0] == 'J' || word == "CALL"
+}
+
+func arch386() *Arch {
+	register := make(map[string]int16)
+	// Create maps for easy lookup of instruction names etc.
+	// TODO: Should this be done in obj for us?
+	for i, s := range i386.Register {
+		register[s] = int16(i + i386.REG_AL)
+	}
+	// Pseudo-registers.
+	register["SB"] = RSB
+	register["FP"] = RFP
+	register["PC"] = RPC
+	// Prefixes not used on this architecture.
+
+	instructions := make(map[string]int)
+	for i, s := range i386.Anames {
+		instructions[s] = i
+	}
+	// Annoying aliases.
+	instructions["JA"] = i386.AJHI
+	instructions["JAE"] = i386.AJCC
+	instructions["JB"] = i386.AJCS
+	instructions["JBE"] = i386.AJLS
+	instructions["JC"] = i386.AJCS
+	instructions["JE"] = i386.AJEQ
+	instructions["JG"] = i386.AJGT
+	instructions["JHS"] = i386.AJCC
+	instructions["JL"] = i386.AJLT
+	instructions["JLO"] = i386.AJCS
+	instructions["JNA"] = i386.AJLS
+	instructions["JNAE"] = i386.AJCS
+	instructions["JNB"] = i386.AJCC
+	instructions["JNBE"] = i386.AJHI
+	instructions["JNC"] = i386.AJCC
+	instructions["JNG"] = i386.AJLE
+	instructions["JNGE"] = i386.AJLT
+	instructions["JNL"] = i386.AJGE
+	instructions["JNLE"] = i386.AJGT
+	instructions["JNO"] = i386.AJOC
+	instructions["JNP"] = i386.AJPC
+	instructions["JNS"] = i386.AJPL
+	instructions["JNZ"] = i386.AJNE
+	instructions["JO"] = i386.AJOS
+	instructions["JP"] = i386.AJPS
+	instructions["JPE"] = i386.AJPS
+	instructions["JPO"] = i386.AJPC
+	instructions["JS"] = i386.AJMI
+	instructions["JZ"] = i386.AJEQ
+	instructions["MASKMOVDQU"] = i386.AMASKMOVOU
+	instructions["MOVOA"] = i386.AMOVO
+	instructions["MOVNTDQ"] = i386.AMOVNTO
+
+	return &Arch{
+		LinkArch:       &i386.Link386,
+		Instructions:   instructions,
+		Register:       register,
+		RegisterPrefix: nil,
+		RegisterNumber: nilRegisterNumber,
+		IsJump:         jump386,
+		Aconv:          i386.Aconv,
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package arch
+
+import "cmd/internal/obj/x86" // == amd64
+
+func init() {
+	Register("amd64", archAmd64)
+	Register("amd64p32", func() *Arch {
+		a := archAmd64()
+		a.LinkArch = &x86.Linkamd64p32
+		return a
+	})
+}
+
+func archAmd64() *Arch {
+	register := make(map[string]int16)
+	// Create maps for easy lookup of instruction names etc.
+	// TODO: Should this be done in obj for us?
+	for i, s := range x86.Register {
+		register[s] = int16(i + x86.REG_AL)
+	}
+	// Pseudo-registers.
+	register["SB"] = RSB
+	register["FP"] = RFP
+	register["PC"] = RPC
+	// Register prefix not used on this architecture.
+
+	instructions := make(map[string]int)
+	for i, s := range x86.Anames {
+		instructions[s] = i
+	}
+	// Annoying aliases.
+	instructions["JA"] = x86.AJHI
+	instructions["JAE"] = x86.AJCC
+	instructions["JB"] = x86.AJCS
+	instructions["JBE"] = x86.AJLS
+	instructions["JC"] = x86.AJCS
+	instructions["JE"] = x86.AJEQ
+	instructions["JG"] = x86.AJGT
+	instructions["JHS"] = x86.AJCC
+	instructions["JL"] = x86.AJLT
+	instructions["JLO"] = x86.AJCS
+	instructions["JNA"] = x86.AJLS
+	instructions["JNAE"] = x86.AJCS
+	instructions["JNB"] = x86.AJCC
+	instructions["JNBE"] = x86.AJHI
+	instructions["JNC"] = x86.AJCC
+	instructions["JNG"] = x86.AJLE
+	instructions["JNGE"] = x86.AJLT
+	instructions["JNL"] = x86.AJGE
+	instructions["JNLE"] = x86.AJGT
+	instructions["JNO"] = x86.AJOC
+	instructions["JNP"] = x86.AJPC
+	instructions["JNS"] = x86.AJPL
+	instructions["JNZ"] = x86.AJNE
+	instructions["JO"] = x86.AJOS
+	instructions["JP"] = x86.AJPS
+	instructions["JPE"] = x86.AJPS
+	instructions["JPO"] = x86.AJPC
+	instructions["JS"] = x86.AJMI
+	instructions["JZ"] = x86.AJEQ
+	instructions["MASKMOVDQU"] = x86.AMASKMOVOU
+	instructions["MOVD"] = x86.AMOVQ
+	instructions["MOVDQ2Q"] = x86.AMOVQ
+	instructions["MOVNTDQ"] = x86.AMOVNTO
+	instructions["MOVOA"] = x86.AMOVO
+	instructions["MOVOA"] = x86.AMOVO
+	instructions["PF2ID"] = x86.APF2IL
+	instructions["PI2FD"] = x86.API2FL
+	instructions["PSLLDQ"] = x86.APSLLO
+	instructions["PSRLDQ"] = x86.APSRLO
+
+	return &Arch{
+		LinkArch:       &x86.Linkamd64,
+		Instructions:   instructions,
+		Register:       register,
+		RegisterPrefix: nil,
+		RegisterNumber: nilRegisterNumber,
+		IsJump:         jump386,
+		Aconv:          x86.Aconv,
+	}
+}
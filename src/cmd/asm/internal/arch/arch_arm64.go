@@ -0,0 +1,99 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package arch
+
+import (
+	"cmd/internal/obj"
+	"cmd/internal/obj/arm64"
+)
+
+func init() {
+	Register("arm64", archArm64)
+}
+
+func archArm64() *Arch {
+	register := make(map[string]int16)
+	// Create maps for easy lookup of instruction names etc.
+	// TODO: Should this be done in obj for us?
+	// Note that there is no list of names as there is for 386 and amd64.
+	for i := arm64.REG_R0; i <= arm64.REG_R31; i++ {
+		register[obj.Rconv(i)] = int16(i)
+	}
+	for i := arm64.REG_F0; i <= arm64.REG_F31; i++ {
+		register[obj.Rconv(i)] = int16(i)
+	}
+	for i := arm64.REG_V0; i <= arm64.REG_V31; i++ {
+		register[obj.Rconv(i)] = int16(i)
+	}
+	register["FPSR"] = arm64.REG_FPSR
+	register["FPCR"] = arm64.REG_FPCR
+	register["NZCV"] = arm64.REG_NZCV
+	register["LR"] = arm64.REGLINK
+	register["ZR"] = arm64.REGZERO
+	// Pseudo-registers.
+	register["SB"] = RSB
+	register["FP"] = RFP
+	register["PC"] = RPC
+	register["SP"] = RSP
+	// Avoid unintentionally clobbering g using R28.
+	delete(register, "R28")
+	register["g"] = arm64.REG_R28
+	registerPrefix := map[string]bool{
+		"F": true,
+		"R": true,
+		"V": true,
+	}
+
+	instructions := make(map[string]int)
+	for i, s := range arm64.Anames {
+		instructions[s] = i
+	}
+	// Annoying aliases.
+	instructions["B"] = obj.AJMP
+	instructions["BL"] = obj.ACALL
+	instructions["RET"] = arm64.ARETURN
+	instructions["CBZ"] = arm64.ACBZ
+	instructions["CBZW"] = arm64.ACBZW
+	instructions["CBNZ"] = arm64.ACBNZ
+	instructions["CBNZW"] = arm64.ACBNZW
+
+	return &Arch{
+		LinkArch:       &arm64.Linkarm64,
+		Instructions:   instructions,
+		Register:       register,
+		RegisterPrefix: registerPrefix,
+		RegisterNumber: arm64RegisterNumber,
+		IsJump:         jumpArm64,
+		Aconv:          arm64.Aconv,
+	}
+}
+
+// arm64RegisterNumber converts R(10) into arm64.REG_R10, F(3) into
+// arm64.REG_F3, V(3) into arm64.REG_V3.
+func arm64RegisterNumber(name string, n int16) (int16, bool) {
+	if n < 0 || n > 31 {
+		return 0, false
+	}
+	switch name {
+	case "R":
+		return arm64.REG_R0 + n, true
+	case "F":
+		return arm64.REG_F0 + n, true
+	case "V":
+		return arm64.REG_V0 + n, true
+	}
+	return 0, false
+}
+
+func jumpArm64(word string) bool {
+	switch word {
+	case "CALL", "B", "BL",
+		"BEQ", "BNE", "BCS", "BHS", "BCC", "BLO", "BMI", "BPL", "BVS", "BVC",
+		"BHI", "BLS", "BGE", "BLT", "BGT", "BLE",
+		"CBZ", "CBZW", "CBNZ", "CBNZW", "TBZ", "TBNZ":
+		return true
+	}
+	return false
+}
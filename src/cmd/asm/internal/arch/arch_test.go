@@ -0,0 +1,99 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package arch
+
+import (
+	"cmd/internal/obj"
+	"cmd/internal/obj/arm64"
+	"testing"
+)
+
+func TestArm64RegisterNumber(t *testing.T) {
+	a := archArm64()
+	tests := []struct {
+		name string
+		n    int16
+		ok   bool
+	}{
+		{"R", 0, true},
+		{"R", 31, true},
+		{"R", 32, false},
+		{"F", 0, true},
+		{"F", 31, true},
+		{"F", 32, false},
+		{"V", 0, true},
+		{"V", 31, true},
+		{"V", 32, false},
+	}
+	for _, test := range tests {
+		_, ok := a.RegisterNumber(test.name, test.n)
+		if ok != test.ok {
+			t.Errorf("RegisterNumber(%q, %d) ok = %v, want %v", test.name, test.n, ok, test.ok)
+		}
+	}
+}
+
+func TestArm64RegisterPrefix(t *testing.T) {
+	a := archArm64()
+	for _, prefix := range []string{"R", "F", "V"} {
+		if !a.RegisterPrefix[prefix] {
+			t.Errorf("RegisterPrefix[%q] = false, want true", prefix)
+		}
+	}
+}
+
+func TestArm64FRegisterTable(t *testing.T) {
+	a := archArm64()
+	if _, ok := a.Register["F0"]; !ok {
+		t.Error(`Register["F0"] missing`)
+	}
+	if _, ok := a.Register["F31"]; !ok {
+		t.Error(`Register["F31"] missing`)
+	}
+}
+
+func TestSetKnowsAllListedArchs(t *testing.T) {
+	for _, goarch := range List() {
+		if Set(goarch) == nil {
+			t.Errorf("Set(%q) = nil, but %q is in List()", goarch, goarch)
+		}
+	}
+}
+
+func TestGenericDconv(t *testing.T) {
+	a := Set("arm64")
+	g, ok := a.Register["g"]
+	if !ok {
+		t.Fatal(`Register["g"] missing`)
+	}
+
+	none := obj.Addr{Type: obj.TYPE_NONE}
+	if s := a.Dconv(&none); s != "" {
+		t.Errorf("Dconv(TYPE_NONE) = %q, want empty string", s)
+	}
+
+	reg := obj.Addr{Type: obj.TYPE_REG, Reg: g}
+	if s := a.Dconv(&reg); s != "g" {
+		t.Errorf("Dconv(TYPE_REG g) = %q, want %q", s, "g")
+	}
+
+	mem := obj.Addr{Type: obj.TYPE_MEM, Reg: g, Offset: 8}
+	if s := a.Dconv(&mem); s != "8(g)" {
+		t.Errorf("Dconv(TYPE_MEM) = %q, want %q", s, "8(g)")
+	}
+}
+
+func TestGenericPconvSkipsEmptyOperands(t *testing.T) {
+	a := Set("arm64")
+	p := &obj.Prog{
+		As:   arm64.ARETURN,
+		From: obj.Addr{Type: obj.TYPE_NONE},
+		To:   obj.Addr{Type: obj.TYPE_NONE},
+	}
+	want := a.Aconv(int(arm64.ARETURN))
+	if s := a.Pconv(p); s != want {
+		t.Errorf("Pconv(RET with no operands) = %q, want %q", s, want)
+	}
+}